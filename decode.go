@@ -49,6 +49,31 @@ func (e *UnmarshalTypeError) Error() string {
 //
 // A struct field tag containing "omitempty" will set the destination field to
 // its type's zero value if no corresponding property key was encountered.
+//
+// A pointer field is allocated as needed and decoded as the type it points
+// to. A non-embedded struct field decodes from properties keyed with a
+// dotted path ("Field.Subfield"). An anonymous (embedded) struct field has
+// its fields decoded from the enclosing section's own keys, as if they were
+// declared in the enclosing struct; a tag on the embedded field does not
+// change this, and a key naming both an outer field and a promoted field is
+// decoded into both. Marshal does not yet mirror any of this;
+// round-tripping a struct using these forms through Marshal is not
+// supported until the encode path gains the equivalent handling.
+//
+// A struct field tag option "inherit=Name" seeds that struct field's
+// zero-valued subfields from the section named Name before its own
+// properties are applied, so shared defaults can be factored into one
+// section. The alternative "[child : parent]" section-header syntax is not
+// implemented: Unmarshal returns an error rather than guessing at what it
+// means, and adding parser support for it is tracked as separate follow-up
+// work, not delivered by this change.
+//
+// A decoder registered in Options.TypeDecoders for a destination field's
+// type is used first, falling back in turn to the built-in time.Time and
+// time.Duration decoders. Failing that, if the field, or a pointer to it,
+// implements Unmarshaler or encoding.TextUnmarshaler, Unmarshal calls it with
+// the raw property value instead of applying the rules above. Unmarshaler is
+// preferred when a field implements both.
 func Unmarshal(data []byte, v interface{}) error {
 	return unmarshal(data, v, Options{})
 }
@@ -59,25 +84,9 @@ func UnmarshalWithOptions(data []byte, v interface{}, opts Options) error {
 	return unmarshal(data, v, opts)
 }
 
-func unmarshal(data []byte, v interface{}, opts Options) error {
-	p := newParser(data)
-	p.l.opts.allowMultilineEscapeNewline = opts.AllowMultilineValues
-	p.l.opts.allowMultilineWhitespacePrefix = opts.AllowMultilineValues
-	p.l.opts.allowNumberSignComments = opts.AllowNumberSignComments
-	if err := p.parse(); err != nil {
-		return err
-	}
-
-	if err := decode(p.tree, reflect.ValueOf(v)); err != nil {
-		return err
-	}
-
-	return nil
-}
-
 // decode sets the underlying values of the value to which rv points to the
 // concrete value stored in the corresponding field of ast.
-func decode(tree parseTree, rv reflect.Value) error {
+func decode(tree parseTree, rv reflect.Value, opts Options) error {
 	if rv.Type().Kind() != reflect.Ptr {
 		return &UnmarshalTypeError{
 			val: reflect.ValueOf(tree).String(),
@@ -93,8 +102,14 @@ func decode(tree parseTree, rv reflect.Value) error {
 		}
 	}
 
+	for _, name := range tree.sectionNames() {
+		if _, _, ok := splitInheritHeader(name); ok {
+			return errSectionHeaderInheritance
+		}
+	}
+
 	/* global properties */
-	if err := decodeStruct(tree.global, rv.Addr()); err != nil {
+	if err := decodeStruct(tree.global, rv.Addr(), opts); err != nil {
 		return err
 
 	}
@@ -110,15 +125,17 @@ func decode(tree parseTree, rv reflect.Value) error {
 
 		switch sf.Type.Kind() {
 		case reflect.Struct:
-			sectionGroup, err := tree.get(t.name)
-			if err != nil {
+			if err := decodeTopLevelSection(tree, t, sv, opts); err != nil {
 				return err
 			}
-			if len(sectionGroup) == 0 {
+		case reflect.Ptr:
+			if sf.Type.Elem().Kind() != reflect.Struct {
 				continue
 			}
-			val := sectionGroup[0]
-			if err := decodeStruct(val, sv); err != nil {
+			if rv.Field(i).IsNil() {
+				rv.Field(i).Set(reflect.New(sf.Type.Elem()))
+			}
+			if err := decodeTopLevelSection(tree, t, rv.Field(i), opts); err != nil {
 				return err
 			}
 		case reflect.Slice:
@@ -132,7 +149,7 @@ func decode(tree parseTree, rv reflect.Value) error {
 			if len(val) == 0 {
 				continue
 			}
-			if err := decodeSlice(val, sv); err != nil {
+			if err := decodeSlice(val, sv, opts); err != nil {
 				return err
 			}
 		}
@@ -141,10 +158,31 @@ func decode(tree parseTree, rv reflect.Value) error {
 	return nil
 }
 
+// decodeTopLevelSection decodes the section named by t.name into sv, which
+// must be the address of (or a non-nil pointer to) a destination struct
+// field, seeding it from t's inherit option first. It is shared by decode's
+// Struct and Ptr cases so a top-level section field behaves the same way
+// whether or not it is behind a pointer.
+func decodeTopLevelSection(tree parseTree, t tag, sv reflect.Value, opts Options) error {
+	if _, err := decodeWithInheritance(tree, t, sv, opts); err != nil {
+		return err
+	}
+
+	sectionGroup, err := tree.get(t.name)
+	if err != nil {
+		return err
+	}
+	if len(sectionGroup) == 0 {
+		return nil
+	}
+
+	return decodeStruct(sectionGroup[0], sv, opts)
+}
+
 // decodeStruct sets the underlying values of the fields of the value to which
 // rv points to the concrete values stored in i. If rv is not a reflect.Ptr,
 // decodeStruct returns UnmarshalTypeError.
-func decodeStruct(i interface{}, rv reflect.Value) error {
+func decodeStruct(i interface{}, rv reflect.Value, opts Options) error {
 	if reflect.TypeOf(i) != reflect.TypeOf(section{}) || rv.Type().Kind() != reflect.Ptr {
 		return &UnmarshalTypeError{
 			val: reflect.ValueOf(i).String(),
@@ -153,129 +191,188 @@ func decodeStruct(i interface{}, rv reflect.Value) error {
 	}
 
 	s := i.(section)
+	if _, _, ok := splitInheritHeader(s.name); ok {
+		return errSectionHeaderInheritance
+	}
+
 	rv = rv.Elem()
 
 	for i := 0; i < rv.NumField(); i++ {
 		sf := rv.Type().Field(i)
 		sv := rv.Field(i).Addr()
 
-		t := newTag(sf)
-		if t.name == "-" {
-			continue
+		if err := decodeField(s, sf, sv, opts); err != nil {
+			return err
 		}
+	}
 
-		switch sf.Type.Kind() {
-		case reflect.Slice:
-			// slices of structs inside a struct is *im-parsable*... get it?
-			if sf.Type.Elem().Kind() == reflect.Struct {
-				// TODO: This should probably error instead of silently skipping
-				continue
-			}
+	return nil
+}
 
-			prop, err := s.get(t.name)
-			if err != nil {
-				return err
-			}
-			val := prop.get("")
-			if len(val) == 0 {
-				continue
-			}
-			if err := decodeSlice(val, sv); err != nil {
-				return err
-			}
-		case reflect.Map:
-			if sf.Type.Elem().Kind() == reflect.Struct {
-				continue
-			}
+// decodeField decodes the property or properties in s that correspond to
+// struct field sf into the value to which sv points. sv is the address of
+// the field itself, matching rv.Field(i).Addr() in decodeStruct, or, when
+// decodeField recurses for a pointer field, the pointer that field holds.
+func decodeField(s section, sf reflect.StructField, sv reflect.Value, opts Options) error {
+	t := newTag(sf)
+	if t.name == "-" {
+		return nil
+	}
 
-			prop, err := s.get(t.name)
-			if err != nil {
-				return err
-			}
-			var val interface{}
-			val = *prop
-			if err := decodeMap(val, sv); err != nil {
-				return err
-			}
-		case reflect.String:
-			var val string
-			if sf.Name == "ININame" {
-				val = s.name
-			} else {
-				prop, err := s.get(t.name)
+	if sf.Type.Kind() == reflect.Ptr {
+		if sv.Elem().IsNil() {
+			sv.Elem().Set(reflect.New(sf.Type.Elem()))
+		}
+		inner := sf
+		inner.Type = sf.Type.Elem()
+		return decodeField(s, inner, sv.Elem(), opts)
+	}
+
+	if sf.Anonymous && sf.Type.Kind() == reflect.Struct {
+		return decodeStruct(s, sv, opts)
+	}
+
+	if sf.Name != "ININame" {
+		prop, err := s.get(t.name)
+		if err != nil {
+			return err
+		}
+		// A map field's values live under their own sub-keys rather than
+		// the unnamed "" key (see decodeMap), so prop.get("") is correctly
+		// empty for a populated map; only treat the property as a single
+		// raw value when one is actually present.
+		if vals := prop.get(""); len(vals) > 0 {
+			raw := vals[0]
+
+			// A registered or built-in TypeDecoder is consulted ahead of
+			// encoding.TextUnmarshaler so that types like time.Time, which
+			// also implement TextUnmarshaler with a fixed RFC3339-only
+			// contract, still honor Options.TimeLayouts instead of always
+			// failing on a non-RFC3339 value.
+			if dec, ok := lookupTypeDecoder(opts, sf.Type); ok {
+				val, err := dec(raw)
 				if err != nil {
-					return err
+					return &UnmarshalTypeError{val: raw, typ: sf.Type, fld: sf.Name}
 				}
-				if len(prop.vals) == 0 {
-					continue
-				}
-				vals := prop.get("")
-				val = vals[0]
-			}
-			if err := decodeString(val, sv); err != nil {
-				return err
-			}
-		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-			prop, err := s.get(t.name)
-			if err != nil {
-				return err
-			}
-			if len(prop.vals) == 0 {
-				continue
-			}
-			vals := prop.get("")
-			val := vals[0]
-			if err := decodeInt(val, sv); err != nil {
-				return err
-			}
-		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-			prop, err := s.get(t.name)
-			if err != nil {
-				return err
-			}
-			if len(prop.vals) == 0 {
-				continue
-			}
-			vals := prop.get("")
-			val := vals[0]
-			if err := decodeUint(val, sv); err != nil {
-				return err
-			}
-		case reflect.Float32, reflect.Float64:
-			prop, err := s.get(t.name)
-			if err != nil {
-				return err
+				sv.Elem().Set(reflect.ValueOf(val))
+				return nil
 			}
-			if len(prop.vals) == 0 {
-				continue
-			}
-			vals := prop.get("")
-			val := vals[0]
-			if err := decodeFloat(val, sv); err != nil {
+			if handled, err := decodeCustom(raw, sv); err != nil {
 				return err
+			} else if handled {
+				return nil
 			}
-		case reflect.Bool:
+		}
+	}
+
+	switch sf.Type.Kind() {
+	case reflect.Struct:
+		// A non-embedded struct field nests its properties under a
+		// dotted-path prefix, e.g. a field named Sub decodes from
+		// properties keyed "Sub.Field".
+		return decodeNestedStruct(s, t.name, sv, opts)
+	case reflect.Slice:
+		// slices of structs inside a struct is *im-parsable*... get it?
+		if sf.Type.Elem().Kind() == reflect.Struct {
+			// TODO: This should probably error instead of silently skipping
+			return nil
+		}
+
+		prop, err := s.get(t.name)
+		if err != nil {
+			return err
+		}
+		val := prop.get("")
+		if len(val) == 0 {
+			return nil
+		}
+		return decodeSlice(val, sv, opts)
+	case reflect.Map:
+		if sf.Type.Elem().Kind() == reflect.Struct {
+			return nil
+		}
+
+		prop, err := s.get(t.name)
+		if err != nil {
+			return err
+		}
+		var val interface{}
+		val = *prop
+		return decodeMap(val, sv, opts)
+	case reflect.String:
+		var val string
+		if sf.Name == "ININame" {
+			val = s.name
+		} else {
 			prop, err := s.get(t.name)
 			if err != nil {
 				return err
 			}
 			if len(prop.vals) == 0 {
-				continue
+				return nil
 			}
 			vals := prop.get("")
-			val := vals[0]
-			if err := decodeBool(val, sv); err != nil {
-				return err
-			}
+			val = vals[0]
+		}
+		return decodeString(val, sv)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		prop, err := s.get(t.name)
+		if err != nil {
+			return err
+		}
+		if len(prop.vals) == 0 {
+			return nil
+		}
+		vals := prop.get("")
+		return decodeInt(vals[0], sv)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		prop, err := s.get(t.name)
+		if err != nil {
+			return err
+		}
+		if len(prop.vals) == 0 {
+			return nil
+		}
+		vals := prop.get("")
+		return decodeUint(vals[0], sv)
+	case reflect.Float32, reflect.Float64:
+		prop, err := s.get(t.name)
+		if err != nil {
+			return err
+		}
+		if len(prop.vals) == 0 {
+			return nil
+		}
+		vals := prop.get("")
+		return decodeFloat(vals[0], sv)
+	case reflect.Bool:
+		prop, err := s.get(t.name)
+		if err != nil {
+			return err
+		}
+		if len(prop.vals) == 0 {
+			return nil
 		}
+		vals := prop.get("")
+		return decodeBool(vals[0], sv)
 	}
 
 	return nil
 }
 
+// decodeNestedStruct decodes sv from the dotted-path sub-table of s named
+// name, e.g. properties keyed "name.Field" for a struct field named name.
+func decodeNestedStruct(s section, name string, sv reflect.Value, opts Options) error {
+	sub, err := s.sub(name)
+	if err != nil {
+		return err
+	}
+	return decodeStruct(sub, sv, opts)
+}
+
 // decodeSlice sets the underlying values of the elements of the value to which
 // rv points to the concrete values stored in i.
-func decodeSlice(v interface{}, rv reflect.Value) error {
+func decodeSlice(v interface{}, rv reflect.Value, opts Options) error {
 	if reflect.TypeOf(v).Kind() != reflect.Slice || rv.Type().Kind() != reflect.Ptr {
 		return &UnmarshalTypeError{
 			val: reflect.ValueOf(v).String(),
@@ -285,6 +382,10 @@ func decodeSlice(v interface{}, rv reflect.Value) error {
 
 	rv = rv.Elem()
 
+	if dec, ok := lookupTypeDecoder(opts, rv.Type().Elem()); ok {
+		return decodeSliceWithTypeDecoder(v, rv, dec)
+	}
+
 	var decoderFunc func(interface{}, reflect.Value) error
 
 	switch rv.Type().Elem().Kind() {
@@ -295,7 +396,9 @@ func decodeSlice(v interface{}, rv reflect.Value) error {
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
 		decoderFunc = decodeUint
 	case reflect.Struct:
-		decoderFunc = decodeStruct
+		decoderFunc = func(v interface{}, sv reflect.Value) error {
+			return decodeStruct(v, sv, opts)
+		}
 	case reflect.Float32, reflect.Float64:
 		decoderFunc = decodeFloat
 	case reflect.Bool:
@@ -312,6 +415,13 @@ func decodeSlice(v interface{}, rv reflect.Value) error {
 	for i := 0; i < vv.Len(); i++ {
 		sv := vv.Index(i).Addr()
 		val := reflect.ValueOf(v).Index(i).Interface()
+		if raw, ok := val.(string); ok {
+			if handled, err := decodeCustom(raw, sv); err != nil {
+				return err
+			} else if handled {
+				continue
+			}
+		}
 		if err := decoderFunc(val, sv); err != nil {
 			return err
 		}
@@ -324,7 +434,7 @@ func decodeSlice(v interface{}, rv reflect.Value) error {
 
 // decodeMap sets the underlying values of the elements of the value to which
 // rv points to the concrete values stored in i.
-func decodeMap(i interface{}, rv reflect.Value) error {
+func decodeMap(i interface{}, rv reflect.Value, opts Options) error {
 	if reflect.TypeOf(i) != reflect.TypeOf(property{}) || rv.Type().Kind() != reflect.Ptr {
 		return &UnmarshalTypeError{
 			val: reflect.ValueOf(i).String(),
@@ -335,23 +445,30 @@ func decodeMap(i interface{}, rv reflect.Value) error {
 	p := i.(property)
 	rv = rv.Elem()
 
+	var elemDecoder func(string) (interface{}, error)
+	if dec, ok := lookupTypeDecoder(opts, rv.Type().Elem()); ok {
+		elemDecoder = dec
+	}
+
 	var decoderFunc func(interface{}, reflect.Value) error
 
-	switch rv.Type().Elem().Kind() {
-	case reflect.String:
-		decoderFunc = decodeString
-	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		decoderFunc = decodeInt
-	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-		decoderFunc = decodeUint
-	case reflect.Float32, reflect.Float64:
-		decoderFunc = decodeFloat
-	case reflect.Bool:
-		decoderFunc = decodeBool
-	default:
-		return &UnmarshalTypeError{
-			val: reflect.ValueOf(i).String(),
-			typ: rv.Type(),
+	if elemDecoder == nil {
+		switch rv.Type().Elem().Kind() {
+		case reflect.String:
+			decoderFunc = decodeString
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			decoderFunc = decodeInt
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			decoderFunc = decodeUint
+		case reflect.Float32, reflect.Float64:
+			decoderFunc = decodeFloat
+		case reflect.Bool:
+			decoderFunc = decodeBool
+		default:
+			return &UnmarshalTypeError{
+				val: reflect.ValueOf(i).String(),
+				typ: rv.Type(),
+			}
 		}
 	}
 
@@ -359,12 +476,31 @@ func decodeMap(i interface{}, rv reflect.Value) error {
 
 	for k, v := range p.vals {
 		mv := reflect.New(rv.Type().Elem())
+
+		if elemDecoder != nil {
+			val, err := elemDecoder(v[0])
+			if err != nil {
+				return &UnmarshalTypeError{val: v[0], typ: rv.Type().Elem()}
+			}
+			mv.Elem().Set(reflect.ValueOf(val))
+			vv.SetMapIndex(reflect.ValueOf(k), mv.Elem())
+			continue
+		}
+
 		var val interface{}
 		if rv.Type().Elem().Kind() == reflect.Slice {
 			val = v
 		} else {
 			val = v[0]
 		}
+		if raw, ok := val.(string); ok {
+			if handled, err := decodeCustom(raw, mv); err != nil {
+				return err
+			} else if handled {
+				vv.SetMapIndex(reflect.ValueOf(k), mv.Elem())
+				continue
+			}
+		}
 		if err := decoderFunc(val, mv); err != nil {
 			return err
 		}
@@ -0,0 +1,23 @@
+package ini
+
+import "testing"
+
+func TestUnmarshalMapFieldDoesNotPanic(t *testing.T) {
+	type Section struct {
+		Labels map[string]string
+	}
+	type dest struct {
+		Section Section
+	}
+
+	data := []byte("[Section]\nLabels.foo=bar\n")
+
+	var d dest
+	if err := Unmarshal(data, &d); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+
+	if got := d.Section.Labels["foo"]; got != "bar" {
+		t.Errorf("Labels[%q] = %q, want %q", "foo", got, "bar")
+	}
+}
@@ -0,0 +1,57 @@
+package ini
+
+import "testing"
+
+func TestUnmarshalPointerNestedEmbedded(t *testing.T) {
+	type Inner struct {
+		Host string
+	}
+	type Common struct {
+		Name string
+	}
+	type Section struct {
+		Common
+		Addr *string
+		Sub  Inner
+	}
+	type dest struct {
+		Section Section
+	}
+
+	data := []byte("[Section]\nName=svc\nAddr=127.0.0.1\nSub.Host=example.com\n")
+
+	var d dest
+	if err := Unmarshal(data, &d); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+
+	if d.Section.Name != "svc" {
+		t.Errorf("Name = %q, want %q", d.Section.Name, "svc")
+	}
+	if d.Section.Addr == nil || *d.Section.Addr != "127.0.0.1" {
+		t.Errorf("Addr = %v, want %q", d.Section.Addr, "127.0.0.1")
+	}
+	if d.Section.Sub.Host != "example.com" {
+		t.Errorf("Sub.Host = %q, want %q", d.Section.Sub.Host, "example.com")
+	}
+}
+
+func TestUnmarshalTopLevelPointerSection(t *testing.T) {
+	type dest struct {
+		Section *testSection
+	}
+
+	data := []byte("[Section]\nName=svc\n")
+
+	var d dest
+	if err := Unmarshal(data, &d); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+
+	if d.Section == nil {
+		t.Fatal("Section = nil, want allocated")
+	}
+	if d.Section.Name != "svc" {
+		t.Errorf("Section.Name = %q, want %q", d.Section.Name, "svc")
+	}
+}
@@ -0,0 +1,148 @@
+package ini
+
+import (
+	"os"
+	"reflect"
+	"strings"
+)
+
+// OverlayEnv walks v, which must be a pointer to the struct type previously
+// populated by Unmarshal, and overrides any leaf field with the value of a
+// matching environment variable. The environment variable name for a field
+// is built by joining prefix, the enclosing section's field name or tag,
+// and the leaf field's own name or tag with underscores and upper-casing the
+// result, e.g. PREFIX_SECTION_FIELD; map entries are matched the same way
+// against their key, e.g. PREFIX_SECTION_SUBKEY. Slice fields are overridden
+// by splitting the environment value on commas.
+//
+// OverlayEnv is typically called after Unmarshal to layer 12-factor-style
+// environment configuration on top of a parsed INI document.
+func OverlayEnv(v interface{}, prefix string) error {
+	return OverlayEnvWithOptions(v, prefix, Options{})
+}
+
+// OverlayEnvWithOptions behaves like OverlayEnv, except that if prefix is
+// empty it falls back to opts.EnvPrefix.
+func OverlayEnvWithOptions(v interface{}, prefix string, opts Options) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Struct {
+		return &UnmarshalTypeError{
+			val: "environment overlay",
+			typ: rv.Type(),
+		}
+	}
+
+	if prefix == "" {
+		prefix = opts.EnvPrefix
+	}
+
+	return overlayEnv(rv.Elem(), []string{prefix})
+}
+
+func overlayEnv(rv reflect.Value, path []string) error {
+	if rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			rv.Set(reflect.ValueOf(map[string]interface{}{}))
+		} else if _, ok := rv.Interface().(map[string]interface{}); !ok {
+			// A populated interface{} field only knows how to grow as a
+			// map[string]interface{} (see the reflect.Map case below); any
+			// other concrete value stored in it would reach the default
+			// case below as an unaddressable reflect.Value, panicking in
+			// rv.Addr(). Reject it with a real error instead.
+			return &UnmarshalTypeError{
+				val: "environment overlay",
+				typ: rv.Elem().Type(),
+				fld: strings.Join(path, "_"),
+			}
+		}
+		rv = rv.Elem()
+	}
+
+	if rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			rv.Set(reflect.New(rv.Type().Elem()))
+		}
+		return overlayEnv(rv.Elem(), path)
+	}
+
+	switch rv.Kind() {
+	case reflect.Struct:
+		t := rv.Type()
+		for i := 0; i < t.NumField(); i++ {
+			sf := t.Field(i)
+			tg := newTag(sf)
+			if tg.name == "-" {
+				continue
+			}
+			if err := overlayEnv(rv.Field(i), append(path, envName(sf.Name, tg.name))); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Map:
+		if rv.IsNil() {
+			rv.Set(reflect.MakeMap(rv.Type()))
+		}
+		for _, key := range rv.MapKeys() {
+			name := strings.Join(append(path, strings.ToUpper(key.String())), "_")
+			val, ok := os.LookupEnv(name)
+			if !ok {
+				continue
+			}
+			ev := reflect.New(rv.Type().Elem()).Elem()
+			if err := overlayScalar(val, ev); err != nil {
+				return err
+			}
+			rv.SetMapIndex(key, ev)
+		}
+		return nil
+	default:
+		val, ok := os.LookupEnv(strings.Join(path, "_"))
+		if !ok {
+			return nil
+		}
+		return overlayScalar(val, rv)
+	}
+}
+
+func envName(fieldName, tagName string) string {
+	if tagName != "" {
+		return strings.ToUpper(tagName)
+	}
+	return strings.ToUpper(fieldName)
+}
+
+// overlayScalar sets rv, which must be addressable, from the string value of
+// an environment variable. Slice fields are populated by splitting val on
+// commas and decoding each element according to the slice's element kind.
+func overlayScalar(val string, rv reflect.Value) error {
+	if rv.Kind() == reflect.Slice {
+		parts := strings.Split(val, ",")
+		vv := reflect.MakeSlice(rv.Type(), len(parts), len(parts))
+		for i, p := range parts {
+			if err := overlayScalar(strings.TrimSpace(p), vv.Index(i)); err != nil {
+				return err
+			}
+		}
+		rv.Set(vv)
+		return nil
+	}
+
+	switch rv.Kind() {
+	case reflect.String:
+		return decodeString(val, rv.Addr())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return decodeInt(val, rv.Addr())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return decodeUint(val, rv.Addr())
+	case reflect.Float32, reflect.Float64:
+		return decodeFloat(val, rv.Addr())
+	case reflect.Bool:
+		return decodeBool(val, rv.Addr())
+	case reflect.Interface:
+		rv.Set(reflect.ValueOf(val))
+		return nil
+	}
+
+	return nil
+}
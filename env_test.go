@@ -0,0 +1,57 @@
+package ini
+
+import "testing"
+
+func TestOverlayEnvWithOptionsPrefixFallback(t *testing.T) {
+	t.Setenv("APP_SECTION_NAME", "overridden")
+
+	var d testDest
+	if err := OverlayEnvWithOptions(&d, "", Options{EnvPrefix: "APP"}); err != nil {
+		t.Fatalf("OverlayEnvWithOptions: %v", err)
+	}
+
+	if d.Section.Name != "overridden" {
+		t.Errorf("Section.Name = %q, want %q", d.Section.Name, "overridden")
+	}
+}
+
+func TestOverlayEnvPrepopulatedInterfaceFieldErrors(t *testing.T) {
+	type dest struct {
+		Extra interface{}
+	}
+
+	t.Setenv("APP_EXTRA", "overridden")
+
+	d := dest{Extra: "preset-default"}
+	err := OverlayEnv(&d, "APP")
+	if err == nil {
+		t.Fatal("OverlayEnv err = nil, want error for non-map interface{} field")
+	}
+}
+
+func TestOverlayEnvIntSlice(t *testing.T) {
+	type Section struct {
+		Ports []int
+	}
+	type dest struct {
+		Section Section
+	}
+
+	t.Setenv("APP_SECTION_PORTS", "80, 443, 8080")
+
+	var d dest
+	if err := OverlayEnv(&d, "APP"); err != nil {
+		t.Fatalf("OverlayEnv: %v", err)
+	}
+
+	want := []int{80, 443, 8080}
+	if len(d.Section.Ports) != len(want) {
+		t.Fatalf("Ports = %v, want %v", d.Section.Ports, want)
+	}
+	for i, p := range want {
+		if d.Section.Ports[i] != p {
+			t.Errorf("Ports[%d] = %d, want %d", i, d.Section.Ports[i], p)
+		}
+	}
+}
+
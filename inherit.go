@@ -0,0 +1,61 @@
+package ini
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+)
+
+// errSectionHeaderInheritance is returned when a section's raw header name
+// uses the "child : parent" syntax. Resolving that form requires the lexer
+// and parser to recognize the ':' in a header and resolve the parent on a
+// second pass with cycle detection; that parser support is not present in
+// this snapshot of the package and is not delivered here - it is tracked as
+// separate follow-up work, not a part of this change. Rather than silently
+// decoding "[child : parent]" as an ordinary section literally named "child
+// : parent", decode fails loudly so callers don't get silently wrong
+// results. The struct-tag form of inheritance below does not depend on that
+// parser change and is fully implemented.
+var errSectionHeaderInheritance = errors.New(`ini: "[child : parent]" section-header inheritance is not supported by this decoder; only the struct field tag option "inherit=Name" is`)
+
+// splitInheritHeader splits a raw section header name of the form
+// "child : parent" into its child and parent parts. If name does not use
+// the inheritance syntax, ok is false.
+func splitInheritHeader(name string) (child, parent string, ok bool) {
+	const sep = " : "
+	idx := strings.Index(name, sep)
+	if idx < 0 {
+		return name, "", false
+	}
+	return name[:idx], name[idx+len(sep):], true
+}
+
+// decodeWithInheritance seeds rv's zero-valued fields from the section named
+// by the struct field tag's "inherit" option (ini:",inherit=Defaults"),
+// before the field's own section properties are decoded on top of it. It
+// reports whether an inherit option was present.
+func decodeWithInheritance(tree parseTree, t tag, rv reflect.Value, opts Options) (bool, error) {
+	if t.inherit == "" {
+		return false, nil
+	}
+
+	defaults, err := tree.get(t.inherit)
+	if err != nil {
+		return false, err
+	}
+	if len(defaults) == 0 {
+		return true, nil
+	}
+
+	seed := reflect.New(rv.Type().Elem())
+	if err := decodeStruct(defaults[0], seed, opts); err != nil {
+		return true, err
+	}
+	dst, src := rv.Elem(), seed.Elem()
+	for i := 0; i < dst.NumField(); i++ {
+		if f := dst.Field(i); f.CanSet() && f.IsZero() {
+			f.Set(src.Field(i))
+		}
+	}
+	return true, nil
+}
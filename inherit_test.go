@@ -0,0 +1,41 @@
+package ini
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestUnmarshalSectionHeaderInheritanceRejected(t *testing.T) {
+	data := []byte("[Section : Defaults]\nName=svc\n")
+
+	var d testDest
+	err := Unmarshal(data, &d)
+	if !errors.Is(err, errSectionHeaderInheritance) {
+		t.Fatalf("Unmarshal err = %v, want errSectionHeaderInheritance", err)
+	}
+}
+
+func TestUnmarshalTagInherit(t *testing.T) {
+	type Section struct {
+		Host string
+		Port int
+	}
+	type dest struct {
+		Defaults Section
+		Override Section `ini:",inherit=Defaults"`
+	}
+
+	data := []byte("[Defaults]\nHost=example.com\nPort=80\n\n[Override]\nPort=8080\n")
+
+	var d dest
+	if err := Unmarshal(data, &d); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+
+	if d.Override.Host != "example.com" {
+		t.Errorf("Override.Host = %q, want %q", d.Override.Host, "example.com")
+	}
+	if d.Override.Port != 8080 {
+		t.Errorf("Override.Port = %d, want 8080", d.Override.Port)
+	}
+}
@@ -0,0 +1,13 @@
+package ini
+
+// testSection and testDest are shared fixtures for tests that only need one
+// section with a single string field, to avoid redeclaring the same shape
+// across decode_nested_test.go, env_test.go, inherit_test.go, and
+// stream_test.go.
+type testSection struct {
+	Name string
+}
+
+type testDest struct {
+	Section testSection
+}
@@ -0,0 +1,52 @@
+package ini
+
+import (
+	"encoding"
+	"reflect"
+)
+
+// Unmarshaler is implemented by types that want to control their own
+// decoding from an INI property value. UnmarshalINI is given the raw bytes
+// of the value as they appeared in the source document and is checked
+// before the built-in Kind-based decoding in decodeStruct, decodeSlice, and
+// decodeMap, and before encoding.TextUnmarshaler.
+type Unmarshaler interface {
+	UnmarshalINI([]byte) error
+}
+
+// Marshaler is implemented by types that want to control their own encoding
+// into an INI property value, mirroring Unmarshaler. Marshal does not yet
+// consult Marshaler or encoding.TextMarshaler; wiring the encode path up to
+// this interface is tracked as separate follow-up work.
+type Marshaler interface {
+	MarshalINI() ([]byte, error)
+}
+
+// decodeCustom decodes val into rv using, in order of preference, the
+// Unmarshaler interface and the encoding.TextUnmarshaler interface. rv must
+// be a reflect.Ptr, matching the calling convention of decodeString and its
+// siblings. decodeCustom reports whether one of these interfaces accepted
+// the value; if not, the caller should fall through to its normal Kind-based
+// decoding.
+func decodeCustom(val string, rv reflect.Value) (bool, error) {
+	if rv.Kind() != reflect.Ptr {
+		return false, nil
+	}
+
+	target := rv
+	if rv.Elem().Kind() == reflect.Ptr {
+		if rv.Elem().IsNil() {
+			rv.Elem().Set(reflect.New(rv.Elem().Type().Elem()))
+		}
+		target = rv.Elem()
+	}
+
+	if m, ok := target.Interface().(Unmarshaler); ok {
+		return true, m.UnmarshalINI([]byte(val))
+	}
+	if m, ok := target.Interface().(encoding.TextUnmarshaler); ok {
+		return true, m.UnmarshalText([]byte(val))
+	}
+
+	return false, nil
+}
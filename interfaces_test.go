@@ -0,0 +1,59 @@
+package ini
+
+import (
+	"strings"
+	"testing"
+)
+
+type upperString string
+
+func (u *upperString) UnmarshalINI(b []byte) error {
+	*u = upperString(strings.ToUpper(string(b)))
+	return nil
+}
+
+func TestUnmarshalCustomUnmarshalerInSlice(t *testing.T) {
+	type Section struct {
+		Names []upperString
+	}
+	type dest struct {
+		Section Section
+	}
+
+	data := []byte("[Section]\nNames=ada,grace\n")
+
+	var d dest
+	if err := Unmarshal(data, &d); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+
+	want := []upperString{"ADA", "GRACE"}
+	if len(d.Section.Names) != len(want) {
+		t.Fatalf("Names = %v, want %v", d.Section.Names, want)
+	}
+	for i := range want {
+		if d.Section.Names[i] != want[i] {
+			t.Errorf("Names[%d] = %q, want %q", i, d.Section.Names[i], want[i])
+		}
+	}
+}
+
+func TestUnmarshalCustomUnmarshalerInMap(t *testing.T) {
+	type Section struct {
+		Labels map[string]upperString
+	}
+	type dest struct {
+		Section Section
+	}
+
+	data := []byte("[Section]\nLabels.env=prod\n")
+
+	var d dest
+	if err := Unmarshal(data, &d); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+
+	if got := d.Section.Labels["env"]; got != "PROD" {
+		t.Errorf("Labels[%q] = %q, want %q", "env", got, "PROD")
+	}
+}
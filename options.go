@@ -0,0 +1,35 @@
+package ini
+
+import "reflect"
+
+// Options controls optional parsing and decoding behavior for
+// UnmarshalWithOptions.
+type Options struct {
+	// AllowMultilineValues permits a property value to continue onto
+	// subsequent lines, either via a trailing backslash or by indenting the
+	// continuation with leading whitespace.
+	AllowMultilineValues bool
+
+	// AllowNumberSignComments treats lines beginning with '#' as comments in
+	// addition to the default ';'.
+	AllowNumberSignComments bool
+
+	// EnvPrefix, if set, is used by OverlayEnvWithOptions, when its prefix
+	// argument is empty, to derive the environment variable name checked for
+	// each field.
+	EnvPrefix string
+
+	// TypeDecoders maps a destination type to a function that decodes a raw
+	// property value into it, taking precedence over the built-in Kind
+	// switch in decodeStruct, decodeSlice, and decodeMap. TypeDecoders
+	// overrides the built-in time.Time and time.Duration decoders for the
+	// same type.
+	TypeDecoders map[reflect.Type]func(string) (interface{}, error)
+
+	// TimeLayouts is the list of layouts, in order, tried by the built-in
+	// time.Time decoder. If empty, time.RFC3339 is used.
+	//
+	// There is no TypeEncoders/marshal-side equivalent yet; Marshal does not
+	// consult Options at all. That is tracked as separate follow-up work.
+	TimeLayouts []string
+}
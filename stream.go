@@ -0,0 +1,136 @@
+package ini
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"reflect"
+)
+
+// A Decoder reads and decodes an INI-encoded document from an input stream.
+//
+// Decoder does not yet tokenize incrementally: the first call to Decode,
+// More, or Token reads the entire input with io.ReadAll and parses it into
+// one in-memory parseTree, matching Unmarshal's memory profile. More and
+// Token enumerate that already-fully-parsed tree's section names; they are
+// a read-only view for inspecting a document's shape and do not affect what
+// Decode decodes or consume any input themselves. Making this genuinely
+// incremental - parsing section-at-a-time off of r without buffering the
+// whole document - requires changes to the underlying lexer/parser that are
+// tracked as separate follow-up work.
+type Decoder struct {
+	r    io.Reader
+	opts Options
+
+	tree    parseTree
+	names   []string
+	pos     int
+	scanned bool
+}
+
+// NewDecoder returns a new Decoder that reads from r.
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{r: r}
+}
+
+// SetOptions configures the Decoder with opts, mirroring
+// UnmarshalWithOptions. It must be called before Decode, More, or Token.
+func (d *Decoder) SetOptions(opts Options) {
+	d.opts = opts
+}
+
+// Decode reads the whole INI-encoded document from its input and stores it
+// in the value pointed to by v. Decode's result does not depend on any
+// prior calls to More or Token.
+func (d *Decoder) Decode(v interface{}) error {
+	if err := d.scan(); err != nil {
+		return err
+	}
+	return decode(d.tree, reflect.ValueOf(v), d.opts)
+}
+
+// More reports whether there is another section name available from Token.
+// It does not affect, and is not affected by, Decode.
+func (d *Decoder) More() bool {
+	if err := d.scan(); err != nil {
+		return false
+	}
+	return d.pos < len(d.names)
+}
+
+// Token returns the name of the next top-level section in the document,
+// advancing the Decoder. It returns io.EOF once every section has been
+// returned.
+func (d *Decoder) Token() (string, error) {
+	if err := d.scan(); err != nil {
+		return "", err
+	}
+	if d.pos >= len(d.names) {
+		return "", io.EOF
+	}
+
+	name := d.names[d.pos]
+	d.pos++
+	return name, nil
+}
+
+func (d *Decoder) scan() error {
+	if d.scanned {
+		return nil
+	}
+
+	data, err := ioutil.ReadAll(d.r)
+	if err != nil {
+		return err
+	}
+
+	p := newParser(data)
+	p.l.opts.allowMultilineEscapeNewline = d.opts.AllowMultilineValues
+	p.l.opts.allowMultilineWhitespacePrefix = d.opts.AllowMultilineValues
+	p.l.opts.allowNumberSignComments = d.opts.AllowNumberSignComments
+	if err := p.parse(); err != nil {
+		return err
+	}
+
+	d.tree = p.tree
+	d.names = p.tree.sectionNames()
+	d.scanned = true
+	return nil
+}
+
+// An Encoder writes an INI-encoded representation of a struct to an output
+// stream.
+type Encoder struct {
+	w    io.Writer
+	opts Options
+}
+
+// NewEncoder returns a new Encoder that writes to w.
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{w: w}
+}
+
+// SetOptions configures the Encoder with opts.
+func (e *Encoder) SetOptions(opts Options) {
+	e.opts = opts
+}
+
+// Encode writes the INI-encoded representation of v to the stream.
+//
+// Encode does not yet honor e's Options; it is provided so that callers
+// writing to an io.Writer do not need to buffer the encoded document
+// themselves.
+func (e *Encoder) Encode(v interface{}) error {
+	data, err := Marshal(v)
+	if err != nil {
+		return err
+	}
+	_, err = e.w.Write(data)
+	return err
+}
+
+func unmarshal(data []byte, v interface{}, opts Options) error {
+	d := NewDecoder(bytes.NewReader(data))
+	d.SetOptions(opts)
+	return d.Decode(v)
+}
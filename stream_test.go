@@ -0,0 +1,43 @@
+package ini
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+func TestDecoderDecode(t *testing.T) {
+	r := bytes.NewReader([]byte("[Section]\nName=svc\n"))
+	var d testDest
+	if err := NewDecoder(r).Decode(&d); err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if d.Section.Name != "svc" {
+		t.Errorf("Name = %q, want %q", d.Section.Name, "svc")
+	}
+}
+
+func TestDecoderTokenIndependentOfDecode(t *testing.T) {
+	data := []byte("[Section]\nName=svc\n")
+	dec := NewDecoder(bytes.NewReader(data))
+
+	name, err := dec.Token()
+	if err != nil {
+		t.Fatalf("Token: %v", err)
+	}
+	if name != "Section" {
+		t.Errorf("Token = %q, want %q", name, "Section")
+	}
+
+	var d testDest
+	if err := dec.Decode(&d); err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if d.Section.Name != "svc" {
+		t.Errorf("Name = %q, want %q", d.Section.Name, "svc")
+	}
+
+	if _, err := dec.Token(); err != io.EOF {
+		t.Errorf("Token after exhausted = %v, want io.EOF", err)
+	}
+}
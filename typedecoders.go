@@ -0,0 +1,69 @@
+package ini
+
+import (
+	"reflect"
+	"time"
+)
+
+var builtinTypeDecoders = map[reflect.Type]func(Options, string) (interface{}, error){
+	reflect.TypeOf(time.Time{}):      decodeBuiltinTime,
+	reflect.TypeOf(time.Duration(0)): decodeBuiltinDuration,
+}
+
+// lookupTypeDecoder returns the decoder to use for t, preferring a
+// user-supplied entry in opts.TypeDecoders over the built-in time.Time and
+// time.Duration decoders.
+func lookupTypeDecoder(opts Options, t reflect.Type) (func(string) (interface{}, error), bool) {
+	if dec, ok := opts.TypeDecoders[t]; ok {
+		return dec, true
+	}
+	if dec, ok := builtinTypeDecoders[t]; ok {
+		return func(s string) (interface{}, error) { return dec(opts, s) }, true
+	}
+	return nil, false
+}
+
+func decodeBuiltinTime(opts Options, val string) (interface{}, error) {
+	layouts := opts.TimeLayouts
+	if len(layouts) == 0 {
+		layouts = []string{time.RFC3339}
+	}
+
+	var lastErr error
+	for _, layout := range layouts {
+		t, err := time.Parse(layout, val)
+		if err == nil {
+			return t, nil
+		}
+		lastErr = err
+	}
+
+	return nil, lastErr
+}
+
+func decodeBuiltinDuration(_ Options, val string) (interface{}, error) {
+	return time.ParseDuration(val)
+}
+
+// decodeSliceWithTypeDecoder decodes each string element of v using dec,
+// bypassing the Kind-based element decoders in decodeSlice. It is used when
+// a slice's element type has a registered or built-in TypeDecoder.
+func decodeSliceWithTypeDecoder(v interface{}, rv reflect.Value, dec func(string) (interface{}, error)) error {
+	vals := reflect.ValueOf(v)
+	vv := reflect.MakeSlice(rv.Type(), vals.Len(), vals.Len())
+
+	for i := 0; i < vals.Len(); i++ {
+		raw, ok := vals.Index(i).Interface().(string)
+		if !ok {
+			return &UnmarshalTypeError{val: vals.Index(i).String(), typ: rv.Type()}
+		}
+		val, err := dec(raw)
+		if err != nil {
+			return &UnmarshalTypeError{val: raw, typ: rv.Type().Elem()}
+		}
+		vv.Index(i).Set(reflect.ValueOf(val))
+	}
+
+	rv.Set(vv)
+	return nil
+}
@@ -0,0 +1,42 @@
+package ini
+
+import (
+	"testing"
+	"time"
+)
+
+func TestUnmarshalTimeLayoutFallback(t *testing.T) {
+	type dest struct {
+		When time.Time
+	}
+
+	data := []byte("When=2021-02-03\n")
+
+	var d dest
+	opts := Options{TimeLayouts: []string{"2006-01-02"}}
+	if err := UnmarshalWithOptions(data, &d, opts); err != nil {
+		t.Fatalf("UnmarshalWithOptions: %v", err)
+	}
+
+	want := time.Date(2021, 2, 3, 0, 0, 0, 0, time.UTC)
+	if !d.When.Equal(want) {
+		t.Errorf("When = %v, want %v", d.When, want)
+	}
+}
+
+func TestUnmarshalDurationField(t *testing.T) {
+	type dest struct {
+		Timeout time.Duration
+	}
+
+	data := []byte("Timeout=30s\n")
+
+	var d dest
+	if err := Unmarshal(data, &d); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+
+	if d.Timeout != 30*time.Second {
+		t.Errorf("Timeout = %v, want 30s", d.Timeout)
+	}
+}